@@ -0,0 +1,14 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+const (
+	proposeParamChangeID uint8 = 0x6
+	voteParamChangeID    uint8 = 0x7
+
+	proposeParamChangeUnits uint64 = 1
+	voteParamChangeUnits    uint64 = 1
+
+	maxPatchSize = 512 // bytes; a JSON-encoded params.Patch is well under this
+)
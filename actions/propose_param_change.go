@@ -0,0 +1,79 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	hutils "github.com/ava-labs/hypersdk/utils"
+
+	"github.com/ava-labs/indexvm/params"
+)
+
+var _ chain.Action = (*ProposeParamChange)(nil)
+
+// ProposeParamChange submits a new params.Patch for the configured
+// Governors to vote on. The actor must be a Governor.
+type ProposeParamChange struct {
+	Patch params.Patch `json:"patch"`
+}
+
+func (*ProposeParamChange) GetTypeID() uint8 {
+	return proposeParamChangeID
+}
+
+func (*ProposeParamChange) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (*ProposeParamChange) MaxUnits(chain.Rules) uint64 {
+	return proposeParamChangeUnits
+}
+
+func (*ProposeParamChange) StateKeys(_ crypto.PublicKey, txID ids.ID) [][]byte {
+	return [][]byte{
+		params.ConfigKey(),
+		params.ProposalKey(txID),
+	}
+}
+
+func (p *ProposeParamChange) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	db chain.Database,
+	_ int64,
+	actor crypto.PublicKey,
+	txID ids.ID,
+	height uint64,
+) (result *chain.Result, err error) {
+	if err := params.Propose(ctx, db, actor, txID, p.Patch, height); err != nil {
+		return &chain.Result{Success: false, Output: hutils.ErrBytes(err)}, nil
+	}
+	return &chain.Result{Success: true, Output: txID[:]}, nil
+}
+
+func (p *ProposeParamChange) Marshal(pk *codec.Packer) {
+	patchBytes, _ := json.Marshal(p.Patch)
+	pk.PackBytes(patchBytes)
+}
+
+func UnmarshalProposeParamChange(p *codec.Packer) (chain.Action, error) {
+	var patchBytes []byte
+	p.UnpackBytes(maxPatchSize, true, &patchBytes)
+	var patch params.Patch
+	if err := json.Unmarshal(patchBytes, &patch); err != nil {
+		return nil, err
+	}
+	return &ProposeParamChange{Patch: patch}, p.Err()
+}
+
+func (p *ProposeParamChange) Size() int {
+	patchBytes, _ := json.Marshal(p.Patch)
+	return codec.BytesLen(patchBytes)
+}
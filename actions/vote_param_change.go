@@ -0,0 +1,74 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	hutils "github.com/ava-labs/hypersdk/utils"
+
+	"github.com/ava-labs/indexvm/params"
+)
+
+var _ chain.Action = (*VoteParamChange)(nil)
+
+// VoteParamChange casts the actor's full Governor weight in favor of an
+// outstanding ProposeParamChange, identified by the txID that created it.
+type VoteParamChange struct {
+	ProposalID ids.ID `json:"proposalID"`
+}
+
+func (*VoteParamChange) GetTypeID() uint8 {
+	return voteParamChangeID
+}
+
+func (*VoteParamChange) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (*VoteParamChange) MaxUnits(chain.Rules) uint64 {
+	return voteParamChangeUnits
+}
+
+func (v *VoteParamChange) StateKeys(actor crypto.PublicKey, _ ids.ID) [][]byte {
+	return [][]byte{
+		params.ConfigKey(),
+		params.ProposalKey(v.ProposalID),
+		params.VoteKey(v.ProposalID, actor),
+		params.PendingQueueKey(),
+	}
+}
+
+func (v *VoteParamChange) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	db chain.Database,
+	_ int64,
+	actor crypto.PublicKey,
+	_ ids.ID,
+	height uint64,
+) (result *chain.Result, err error) {
+	if err := params.Vote(ctx, db, actor, v.ProposalID, height); err != nil {
+		return &chain.Result{Success: false, Output: hutils.ErrBytes(err)}, nil
+	}
+	return &chain.Result{Success: true}, nil
+}
+
+func (v *VoteParamChange) Marshal(p *codec.Packer) {
+	p.PackID(v.ProposalID)
+}
+
+func UnmarshalVoteParamChange(p *codec.Packer) (chain.Action, error) {
+	var v VoteParamChange
+	p.UnpackID(true, &v.ProposalID)
+	return &v, p.Err()
+}
+
+func (*VoteParamChange) Size() int {
+	return ids.IDLen
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package auth derives storage addresses for the authentication schemes
+// indexvm accepts. Only the ed25519 derivation is known to match hypersdk's
+// own auth module (an ed25519 key is its own address); see ParseKey for the
+// bls/secp256r1 caveat.
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+const (
+	ED25519   = "ed25519"
+	BLS       = "bls"
+	SECP256R1 = "secp256r1"
+)
+
+// Numeric type IDs for the schemes above, in the order hypersdk's own
+// ed25519/bls/secp256r1 auth modules register themselves.
+const (
+	ed25519TypeID   byte = 0
+	blsTypeID       byte = 1
+	secp256r1TypeID byte = 2
+)
+
+// All is the set of auth schemes indexvm knows how to derive addresses for.
+var All = []string{ED25519, BLS, SECP256R1}
+
+var ErrUnknownAuthType = errors.New("unknown auth type")
+
+// ParseKey decodes a hex-encoded raw public key and derives the
+// crypto.PublicKey a chain.Database entry is keyed by under the given auth
+// scheme. An ed25519 key is used as its own address, matching
+// utils.ParseAddress's existing bech32 behavior -- this case is correct by
+// construction.
+//
+// BLS and secp256r1 keys are a different length than crypto.PublicKeyLen,
+// so they're collapsed to a fixed-width address by hashing the scheme's
+// numeric type ID together with the raw key. This keeps every downstream
+// storage key the same width regardless of which scheme produced it, but
+// it is this package's own placeholder convention, not hypersdk's real
+// bls/secp256r1 auth module derivation (which this tree doesn't have the
+// crypto libraries to call into): nothing here has been checked against a
+// tx actually signed under those schemes, so a BLS or secp256r1 address
+// computed by ParseKey is not known to match the address hypersdk's own
+// auth verification would derive for the same key. Treat non-ed25519
+// addresses from this function as provisional until that's verified.
+func ParseKey(authType, pubKeyHex string) (crypto.PublicKey, error) {
+	raw, err := hex.DecodeString(pubKeyHex)
+	if err != nil {
+		return crypto.EmptyPublicKey, fmt.Errorf("invalid pubKey %q: %w", pubKeyHex, err)
+	}
+	switch authType {
+	case ED25519:
+		if len(raw) != crypto.PublicKeyLen {
+			return crypto.EmptyPublicKey, fmt.Errorf("ed25519 pubKey must be %d bytes, got %d", crypto.PublicKeyLen, len(raw))
+		}
+		var pk crypto.PublicKey
+		copy(pk[:], raw)
+		return pk, nil
+	case BLS, SECP256R1:
+		typeID := blsTypeID
+		if authType == SECP256R1 {
+			typeID = secp256r1TypeID
+		}
+		h := hashing.ComputeHash256(append([]byte{typeID}, raw...))
+		var pk crypto.PublicKey
+		copy(pk[:], h)
+		return pk, nil
+	default:
+		return crypto.EmptyPublicKey, fmt.Errorf("%w: %s", ErrUnknownAuthType, authType)
+	}
+}
+
+// Allowed reports whether authType is permitted by allowlist. An empty
+// allowlist permits every scheme in All.
+func Allowed(allowlist []string, authType string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	for _, a := range allowlist {
+		if a == authType {
+			return true
+		}
+	}
+	return false
+}
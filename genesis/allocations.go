@@ -0,0 +1,266 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// allocationBatchSize is how many allocations are handed to a single
+// worker-pool task, and so how many allocations share one tracer sub-span.
+const allocationBatchSize = 256
+
+// allocationWorkers bounds how many batches are applied concurrently at
+// once. Lines are decoded sequentially by the streaming loop itself.
+const allocationWorkers = 8
+
+var ErrAllocationsMerkleRootMismatch = errors.New("computed allocations merkle root does not match genesis")
+
+// loadAllocationsFile streams g.AllocationsFile (a local path or an
+// http(s) URL) as newline-delimited JSON CustomAllocation entries. It reads
+// the file twice: verifyAllocationsMerkleRoot first confirms the file's
+// contents hash to g.AllocationsMerkleRoot without writing anything to db,
+// and only once that's confirmed does applyAllocationsFile re-stream the
+// file and apply it. Checking the root after allocations were already
+// written would let a corrupt or tampered file pollute db before the
+// mismatch was ever detected.
+func (g *Genesis) loadAllocationsFile(ctx context.Context, tracer trace.Tracer, db chain.Database, rules *Rules) error {
+	ctx, span := tracer.Start(ctx, "Genesis.loadAllocationsFile")
+	defer span.End()
+
+	if err := g.verifyAllocationsMerkleRoot(ctx, tracer); err != nil {
+		return err
+	}
+	return g.applyAllocationsFile(ctx, tracer, db, rules)
+}
+
+// verifyAllocationsMerkleRoot streams g.AllocationsFile once, accumulating
+// a Merkle Mountain Range root over each raw line, and fails if it doesn't
+// match g.AllocationsMerkleRoot. It never touches db.
+func (g *Genesis) verifyAllocationsMerkleRoot(ctx context.Context, tracer trace.Tracer) error {
+	ctx, span := tracer.Start(ctx, "Genesis.verifyAllocationsMerkleRoot")
+	defer span.End()
+
+	r, err := openAllocationsFile(ctx, g.AllocationsFile)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var acc merkleAccumulator
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		acc.Add(ids.ID(hashing.ComputeHash256Array([]byte(line))))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if root := acc.Root(); root != g.AllocationsMerkleRoot {
+		return fmt.Errorf("%w: got %s, want %s", ErrAllocationsMerkleRootMismatch, root, g.AllocationsMerkleRoot)
+	}
+	return nil
+}
+
+// applyAllocationsFile re-streams g.AllocationsFile, now that
+// verifyAllocationsMerkleRoot has confirmed its contents, and applies it in
+// batches across a worker pool. Writes are serialized per address via
+// keyLocks rather than behind one shared mutex: hypersdk's own execution
+// engine parallelizes transactions whose declared StateKeys are disjoint,
+// so chain.Database is expected to tolerate concurrent writers that never
+// touch the same key, and two allocations for different addresses never
+// do -- only same-address writes need to wait on each other.
+func (g *Genesis) applyAllocationsFile(ctx context.Context, tracer trace.Tracer, db chain.Database, rules *Rules) error {
+	ctx, span := tracer.Start(ctx, "Genesis.applyAllocationsFile")
+	defer span.End()
+
+	r, err := openAllocationsFile(ctx, g.AllocationsFile)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var (
+		locks    = newKeyLocks()
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, allocationWorkers)
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() { firstErr = err })
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	batch := make([]*CustomAllocation, 0, allocationBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		b := batch
+		batch = make([]*CustomAllocation, 0, allocationBatchSize)
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := g.loadAllocationBatch(ctx, tracer, db, rules, locks, b); err != nil {
+				fail(err)
+			}
+		}()
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var alloc CustomAllocation
+		if err := json.Unmarshal([]byte(line), &alloc); err != nil {
+			fail(fmt.Errorf("decoding allocation: %w", err))
+			break
+		}
+		batch = append(batch, &alloc)
+		if len(batch) == allocationBatchSize {
+			flush()
+		}
+	}
+	flush()
+	wg.Wait()
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+func (g *Genesis) loadAllocationBatch(ctx context.Context, tracer trace.Tracer, db chain.Database, rules *Rules, locks *keyLocks, batch []*CustomAllocation) error {
+	_, span := tracer.Start(ctx, "Genesis.loadAllocationBatch")
+	defer span.End()
+
+	for _, alloc := range batch {
+		pk, err := g.allocationKey(alloc)
+		if err != nil {
+			return err
+		}
+		unlock := locks.lock(pk)
+		err = g.applyAllocation(ctx, db, rules, alloc)
+		unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyLocks hands out a per-address *sync.Mutex, created lazily on first
+// use, so callers writing to different addresses never block each other
+// while writes to the same address are still serialized.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[crypto.PublicKey]*sync.Mutex
+}
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{locks: make(map[crypto.PublicKey]*sync.Mutex)}
+}
+
+// lock blocks until pk's lock is held and returns a func to release it.
+func (k *keyLocks) lock(pk crypto.PublicKey) func() {
+	k.mu.Lock()
+	l, ok := k.locks[pk]
+	if !ok {
+		l = &sync.Mutex{}
+		k.locks[pk] = l
+	}
+	k.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+func openAllocationsFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching %s: unexpected status %s", path, resp.Status)
+		}
+		return resp.Body, nil
+	}
+	return os.Open(path)
+}
+
+// merkleAccumulator computes a Merkle Mountain Range root incrementally
+// (peaks[i] is the root of a 2^i-leaf subtree, or ids.Empty if absent), so
+// AllocationsMerkleRoot can be verified while streaming leaves instead of
+// requiring every allocation in memory up front.
+type merkleAccumulator struct {
+	peaks []ids.ID
+}
+
+func (m *merkleAccumulator) Add(leaf ids.ID) {
+	carry := leaf
+	for i := range m.peaks {
+		if m.peaks[i] == ids.Empty {
+			m.peaks[i] = carry
+			return
+		}
+		carry = hashPair(m.peaks[i], carry)
+		m.peaks[i] = ids.Empty
+	}
+	m.peaks = append(m.peaks, carry)
+}
+
+func (m *merkleAccumulator) Root() ids.ID {
+	var root ids.ID
+	set := false
+	for _, p := range m.peaks {
+		if p == ids.Empty {
+			continue
+		}
+		if !set {
+			root, set = p, true
+			continue
+		}
+		root = hashPair(p, root)
+	}
+	return root
+}
+
+func hashPair(a, b ids.ID) ids.ID {
+	buf := make([]byte, 0, 2*ids.IDLen)
+	buf = append(buf, a[:]...)
+	buf = append(buf, b[:]...)
+	return ids.ID(hashing.ComputeHash256Array(buf))
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+func leaf(s string) ids.ID {
+	return ids.ID(hashing.ComputeHash256Array([]byte(s)))
+}
+
+func rootOf(lines ...string) ids.ID {
+	var acc merkleAccumulator
+	for _, l := range lines {
+		acc.Add(leaf(l))
+	}
+	return acc.Root()
+}
+
+func TestMerkleAccumulatorDeterministic(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e"}
+	if rootOf(lines...) != rootOf(lines...) {
+		t.Fatal("same leaves in the same order must produce the same root")
+	}
+}
+
+func TestMerkleAccumulatorOrderSensitive(t *testing.T) {
+	if rootOf("a", "b", "c") == rootOf("c", "b", "a") {
+		t.Fatal("reordering leaves must change the root")
+	}
+}
+
+func TestMerkleAccumulatorDetectsTamperedLine(t *testing.T) {
+	want := rootOf(`{"address":"addr1","balance":1}`, `{"address":"addr2","balance":2}`)
+	got := rootOf(`{"address":"addr1","balance":1}`, `{"address":"addr2","balance":999}`)
+	if want == got {
+		t.Fatal("tampering with a single allocation line must change the root")
+	}
+}
+
+func TestMerkleAccumulatorEmpty(t *testing.T) {
+	var acc merkleAccumulator
+	if acc.Root() != ids.Empty {
+		t.Fatalf("empty accumulator should have the empty root, got %s", acc.Root())
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/chain"
+
+	"github.com/ava-labs/indexvm/params"
+)
+
+// RulesAt returns the economic Rules in effect at height: the fork
+// schedule fixed at genesis (Rules), overridden by whatever the params
+// store currently holds for the mutable fields a passed governance
+// proposal can change. Fee calculation and block limit checks should call
+// this instead of reading the immutable Genesis struct or Rules directly,
+// so a passed ProposeParamChange/VoteParamChange actually takes effect.
+// params.Get is a pure read, so RulesAt is safe to call from read-only
+// paths too; it's the chain package's per-block commit step, not RulesAt,
+// that's responsible for calling params.Activate once per height so due
+// proposals are actually persisted out of the pending queue.
+func (g *Genesis) RulesAt(ctx context.Context, db chain.Database, height uint64, timestamp int64) (*Rules, error) {
+	r := g.Rules(height, timestamp)
+	p, err := params.Get(ctx, db, height)
+	if err != nil {
+		return nil, err
+	}
+	r.MinUnitPrice = p.MinUnitPrice
+	r.UnitPriceChangeDenominator = p.UnitPriceChangeDenominator
+	r.WindowTargetUnits = p.WindowTargetUnits
+	r.MinBlockCost = p.MinBlockCost
+	r.BlockCostChangeDenominator = p.BlockCostChangeDenominator
+	r.WindowTargetBlocks = p.WindowTargetBlocks
+	r.StateLockup = p.StateLockup
+	r.MaxBlockUnits = p.MaxBlockUnits
+	r.MaxBlockTxs = p.MaxBlockTxs
+	return r, nil
+}
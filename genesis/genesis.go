@@ -8,12 +8,17 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/hypersdk/chain"
 	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
 	"github.com/ava-labs/hypersdk/vm"
 
+	"github.com/ava-labs/indexvm/auth"
 	"github.com/ava-labs/indexvm/consts"
+	"github.com/ava-labs/indexvm/params"
+	"github.com/ava-labs/indexvm/statesync"
 	"github.com/ava-labs/indexvm/storage"
 	"github.com/ava-labs/indexvm/utils"
 )
@@ -21,8 +26,23 @@ import (
 var _ vm.Genesis = (*Genesis)(nil)
 
 type CustomAllocation struct {
-	Address string `json:"address"` // bech32 address
+	Address string `json:"address"` // bech32 address, ed25519 only
 	Balance uint64 `json:"balance"`
+
+	// AuthType selects the scheme used to verify txs from this account.
+	// Defaults to auth.ED25519, the only scheme where Address is itself a
+	// parseable bech32 address; other schemes must set PubKey instead.
+	AuthType string `json:"authType,omitempty"`
+	// PubKey is the hex-encoded raw public key, required when AuthType is
+	// not auth.ED25519.
+	PubKey string `json:"pubKey,omitempty"`
+}
+
+// Governor is an address entitled to vote on param change proposals, weighted
+// relative to the other configured Governors.
+type Governor struct {
+	Address string `json:"address"` // bech32 address
+	Weight  uint64 `json:"weight"`
 }
 
 type Genesis struct {
@@ -48,8 +68,52 @@ type Genesis struct {
 	BlockCostChangeDenominator uint64 `json:"blockCostChangeDenominator"`
 	WindowTargetBlocks         uint64 `json:"windowTargetBlocks"` // 10s
 
-	// Allocations
-	CustomAllocation []*CustomAllocation `json:"customAllocation"`
+	// Allocations. For a chain launching with few allocations, list them
+	// inline in CustomAllocation. For millions of allocations, set
+	// AllocationsFile instead (a local path or http(s) URL to a
+	// newline-delimited JSON stream of CustomAllocation entries) along
+	// with AllocationsMerkleRoot so Load can stream and verify them
+	// without holding them all in memory; CustomAllocation is ignored
+	// when AllocationsFile is set.
+	CustomAllocation      []*CustomAllocation `json:"customAllocation"`
+	AllocationsFile       string              `json:"allocationsFile,omitempty"`
+	AllocationsMerkleRoot ids.ID              `json:"allocationsMerkleRoot,omitempty"`
+
+	// Governance: addresses allowed to propose/vote on param changes, the
+	// fraction of total Governor weight required to pass a proposal
+	// (basis points, i.e. out of 10_000), how long a proposal accepts
+	// votes, and how long after passing it takes effect.
+	Governors            []*Governor `json:"governors"`
+	ParamApprovalBps     uint64      `json:"paramApprovalBps"`
+	ParamVotingWindow    uint64      `json:"paramVotingWindow"`    // blocks
+	ParamActivationDelay uint64      `json:"paramActivationDelay"` // blocks
+
+	// AuthAllowlist restricts which CustomAllocation.AuthType values
+	// this chain accepts. An empty list allows every scheme in auth.All.
+	AuthAllowlist []string `json:"authAllowlist,omitempty"`
+
+	// SnapshotManifest, if set, lets a node started with --state-sync
+	// bootstrap from a trusted snapshot instead of replaying
+	// CustomAllocation. See EnableStateSync.
+	SnapshotManifest *SnapshotManifest `json:"snapshotManifest,omitempty"`
+
+	// upgrades is the fork schedule parsed from upgradeBytes in New. It is
+	// not part of the genesis JSON itself (each chain's validators must
+	// agree on it independently, the same way avalanchego nodes agree on
+	// upgrade bytes out-of-band).
+	upgrades []*Upgrade
+
+	// stateSyncEnabled mirrors the node's --state-sync flag. It is set via
+	// EnableStateSync rather than unmarshaled, since it's a per-node
+	// runtime choice, not a property of the chain.
+	stateSyncEnabled bool
+}
+
+// EnableStateSync marks that this node was started with --state-sync, so
+// Load should try to bootstrap from SnapshotManifest (if one is present)
+// rather than replaying CustomAllocation.
+func (g *Genesis) EnableStateSync() {
+	g.stateSyncEnabled = true
 }
 
 func Default() *Genesis {
@@ -74,10 +138,15 @@ func Default() *Genesis {
 		MinBlockCost:               0,
 		BlockCostChangeDenominator: 48,
 		WindowTargetBlocks:         20, // 10s
+
+		// Governance
+		ParamApprovalBps:     5_000, // 50%
+		ParamVotingWindow:    241,   // ~2m @ 500ms/block
+		ParamActivationDelay: 241,   // ~2m @ 500ms/block
 	}
 }
 
-func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
+func New(b []byte, upgradeBytes []byte) (*Genesis, error) {
 	g := Default()
 	if len(b) > 0 {
 		if err := json.Unmarshal(b, g); err != nil {
@@ -90,6 +159,11 @@ func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
 	if g.WindowTargetBlocks == 0 {
 		return nil, ErrInvalidTarget
 	}
+	upgrades, err := parseUpgrades(upgradeBytes)
+	if err != nil {
+		return nil, err
+	}
+	g.upgrades = upgrades
 	return g, nil
 }
 
@@ -101,20 +175,117 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, db chain.Databa
 	ctx, span := tracer.Start(ctx, "Genesis.Load")
 	defer span.End()
 
-	for _, alloc := range g.CustomAllocation {
-		pk, err := utils.ParseAddress(alloc.Address)
-		if err != nil {
-			return err
-		}
-		if _, err := storage.AddUnlockedBalance(ctx, db, pk, alloc.Balance, false); err != nil {
-			return fmt.Errorf("%w: addr=%s, bal=%d", err, alloc.Address, alloc.Balance)
+	// Allocations are loaded at genesis (height 0), so they always use the
+	// base Rules (no upgrade can have activated yet).
+	rules := g.Rules(0, 0)
+
+	// seedGovernance writes the governance config and initial Params.
+	// Deferred to a closure so it can run after a successful state-sync
+	// (so the snapshot's root check below never has to account for keys
+	// seedGovernance itself wrote) while still running unconditionally on
+	// every other path, including the replay fallback.
+	seedGovernance := func() error {
+		governors := make([]*params.Governor, 0, len(g.Governors))
+		for _, gov := range g.Governors {
+			pk, err := utils.ParseAddress(gov.Address)
+			if err != nil {
+				return err
+			}
+			governors = append(governors, &params.Governor{PublicKey: pk, Weight: gov.Weight})
 		}
-		if err := storage.LockBalance(ctx, db, pk, g.StateLockup*2); err != nil {
-			return err
+		return params.SetInitial(ctx, db, &params.Params{
+			MinUnitPrice:               rules.MinUnitPrice,
+			UnitPriceChangeDenominator: rules.UnitPriceChangeDenominator,
+			WindowTargetUnits:          rules.WindowTargetUnits,
+			MinBlockCost:               rules.MinBlockCost,
+			BlockCostChangeDenominator: rules.BlockCostChangeDenominator,
+			WindowTargetBlocks:         rules.WindowTargetBlocks,
+			StateLockup:                rules.StateLockup,
+			MaxBlockUnits:              rules.MaxBlockUnits,
+			MaxBlockTxs:                rules.MaxBlockTxs,
+		}, governors, g.ParamApprovalBps, g.ParamVotingWindow, g.ParamActivationDelay)
+	}
+
+	if g.stateSyncEnabled && g.SnapshotManifest != nil {
+		if err := statesync.Load(ctx, tracer, db, &statesync.Manifest{
+			Height:      g.SnapshotManifest.Height,
+			StateRoot:   g.SnapshotManifest.StateRoot,
+			ChunkHashes: g.SnapshotManifest.ChunkHashes,
+			ChunkURLs:   g.SnapshotManifest.ChunkURLs,
+			TotalBytes:  g.SnapshotManifest.TotalBytes,
+			Algorithm:   g.SnapshotManifest.Algorithm,
+		}); err == nil {
+			// statesync.Load verified db's Merkle root against the
+			// manifest before db had any governance keys in it; seed
+			// governance now, after that check passed, rather than
+			// before attempting it, so the manifest never has to
+			// contain byte-identical governance keys just to pass.
+			return seedGovernance()
 		}
-		if err := storage.SetPermissions(ctx, db, pk, pk, hconsts.MaxUint8, hconsts.MaxUint8); err != nil {
+		// Verification failed (or a chunk couldn't be fetched): fall back
+		// to a full replay of CustomAllocation below. statesync.Load rolls
+		// back any chunk it managed to apply before failing, so db is
+		// still empty here, never a partially-applied snapshot.
+	}
+
+	if err := seedGovernance(); err != nil {
+		return err
+	}
+
+	if g.AllocationsFile != "" {
+		return g.loadAllocationsFile(ctx, tracer, db, rules)
+	}
+
+	for _, alloc := range g.CustomAllocation {
+		if err := g.applyAllocation(ctx, db, rules, alloc); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// applyAllocation credits, locks, and sets permissions/auth type for a
+// single CustomAllocation. It is used both for the inline CustomAllocation
+// slice and, per batch, by the AllocationsFile streaming loader.
+func (g *Genesis) applyAllocation(ctx context.Context, db chain.Database, rules *Rules, alloc *CustomAllocation) error {
+	authType := alloc.AuthType
+	if authType == "" {
+		authType = auth.ED25519
+	}
+	if !auth.Allowed(g.AuthAllowlist, authType) {
+		return fmt.Errorf("%w: %s", ErrAuthTypeNotAllowed, authType)
+	}
+
+	pk, err := g.allocationKey(alloc)
+	if err != nil {
+		return err
+	}
+
+	if _, err := storage.AddUnlockedBalance(ctx, db, pk, alloc.Balance, false); err != nil {
+		return fmt.Errorf("%w: addr=%s, bal=%d", err, alloc.Address, alloc.Balance)
+	}
+	if err := storage.LockBalance(ctx, db, pk, rules.StateLockup*2); err != nil {
+		return err
+	}
+	if err := storage.SetPermissions(ctx, db, pk, pk, hconsts.MaxUint8, hconsts.MaxUint8); err != nil {
+		return err
+	}
+	return storage.SetAuthType(ctx, db, pk, authType)
+}
+
+// allocationKey resolves the crypto.PublicKey a CustomAllocation will be
+// stored under, the same way applyAllocation's own storage calls are
+// keyed. It's shared with this package's other callers (loadAllocationBatch)
+// that need to know an allocation's address before writing it -- e.g. to
+// take a per-address lock -- without duplicating applyAllocation's
+// state-writing logic.
+func (g *Genesis) allocationKey(alloc *CustomAllocation) (crypto.PublicKey, error) {
+	authType := alloc.AuthType
+	if authType == "" {
+		authType = auth.ED25519
+	}
+	if authType == auth.ED25519 && alloc.PubKey == "" {
+		return utils.ParseAddress(alloc.Address)
+	}
+	return auth.ParseKey(authType, alloc.PubKey)
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNonMonotonicUpgrade = errors.New("upgrade activation heights must be strictly increasing")
+	ErrUnknownUpgradeField = errors.New("upgrade contains unknown fields")
+	ErrAuthTypeNotAllowed  = errors.New("auth type not permitted by AuthAllowlist")
+)
+
+// Rules is the subset of Genesis parameters that can be overridden by a
+// scheduled Upgrade. Rules returned by [Genesis.Rules] reflect the base
+// Genesis with every upgrade activated at or before the given height folded
+// in, in order.
+type Rules struct {
+	BaseUnits   uint64
+	StateLockup uint64
+
+	MinUnitPrice               uint64
+	UnitPriceChangeDenominator uint64
+	WindowTargetUnits          uint64
+
+	MinBlockCost               uint64
+	BlockCostChangeDenominator uint64
+	WindowTargetBlocks         uint64
+
+	MaxBlockTxs   int
+	MaxBlockUnits uint64
+}
+
+// GenesisPatch is the set of Rules fields an Upgrade may override. A nil
+// field leaves the value from the base Genesis (or an earlier upgrade)
+// unchanged.
+type GenesisPatch struct {
+	BaseUnits   *uint64 `json:"baseUnits,omitempty"`
+	StateLockup *uint64 `json:"stateLockup,omitempty"`
+
+	MinUnitPrice               *uint64 `json:"minUnitPrice,omitempty"`
+	UnitPriceChangeDenominator *uint64 `json:"unitPriceChangeDenominator,omitempty"`
+	WindowTargetUnits          *uint64 `json:"windowTargetUnits,omitempty"`
+
+	MinBlockCost               *uint64 `json:"minBlockCost,omitempty"`
+	BlockCostChangeDenominator *uint64 `json:"blockCostChangeDenominator,omitempty"`
+	WindowTargetBlocks         *uint64 `json:"windowTargetBlocks,omitempty"`
+
+	MaxBlockTxs   *int    `json:"maxBlockTxs,omitempty"`
+	MaxBlockUnits *uint64 `json:"maxBlockUnits,omitempty"`
+}
+
+// Upgrade is a single scheduled fork rule change. It activates once the
+// chain has reached BOTH ActivationHeight and ActivationTime -- see
+// [Genesis.Rules]. Because upgrades are folded in height order and a patch
+// whose ActivationTime hasn't arrived yet is skipped rather than stopping
+// the fold, a later (higher-height) upgrade can still apply while an
+// earlier one is held back by its own ActivationTime; callers authoring an
+// upgradeBytes schedule should keep ActivationTime non-decreasing
+// alongside ActivationHeight to avoid that ordering surprise.
+type Upgrade struct {
+	Name             string       `json:"name"`
+	ActivationHeight uint64       `json:"activationHeight"`
+	ActivationTime   int64        `json:"activationTime"`
+	Patch            GenesisPatch `json:"patch"`
+}
+
+// parseUpgrades decodes upgradeBytes as a JSON list of [Upgrade] entries. It
+// rejects unknown fields (so a typo'd field name fails genesis load rather
+// than silently no-opping) and activation heights that are not strictly
+// increasing.
+func parseUpgrades(upgradeBytes []byte) ([]*Upgrade, error) {
+	if len(upgradeBytes) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(upgradeBytes))
+	dec.DisallowUnknownFields()
+	var upgrades []*Upgrade
+	if err := dec.Decode(&upgrades); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownUpgradeField, err)
+	}
+	var last uint64
+	for i, u := range upgrades {
+		if i > 0 && u.ActivationHeight <= last {
+			return nil, fmt.Errorf("%w: %q activates at %d, not after %d", ErrNonMonotonicUpgrade, u.Name, u.ActivationHeight, last)
+		}
+		last = u.ActivationHeight
+	}
+	return upgrades, nil
+}
+
+// baseRules returns the Rules implied by the base Genesis, before any
+// upgrades are applied.
+func (g *Genesis) baseRules() *Rules {
+	return &Rules{
+		BaseUnits:   g.BaseUnits,
+		StateLockup: g.StateLockup,
+
+		MinUnitPrice:               g.MinUnitPrice,
+		UnitPriceChangeDenominator: g.UnitPriceChangeDenominator,
+		WindowTargetUnits:          g.WindowTargetUnits,
+
+		MinBlockCost:               g.MinBlockCost,
+		BlockCostChangeDenominator: g.BlockCostChangeDenominator,
+		WindowTargetBlocks:         g.WindowTargetBlocks,
+
+		MaxBlockTxs:   g.MaxBlockTxs,
+		MaxBlockUnits: g.MaxBlockUnits,
+	}
+}
+
+// apply folds a GenesisPatch onto r in place, overriding only the fields the
+// patch sets.
+func (r *Rules) apply(p *GenesisPatch) {
+	if p.BaseUnits != nil {
+		r.BaseUnits = *p.BaseUnits
+	}
+	if p.StateLockup != nil {
+		r.StateLockup = *p.StateLockup
+	}
+	if p.MinUnitPrice != nil {
+		r.MinUnitPrice = *p.MinUnitPrice
+	}
+	if p.UnitPriceChangeDenominator != nil {
+		r.UnitPriceChangeDenominator = *p.UnitPriceChangeDenominator
+	}
+	if p.WindowTargetUnits != nil {
+		r.WindowTargetUnits = *p.WindowTargetUnits
+	}
+	if p.MinBlockCost != nil {
+		r.MinBlockCost = *p.MinBlockCost
+	}
+	if p.BlockCostChangeDenominator != nil {
+		r.BlockCostChangeDenominator = *p.BlockCostChangeDenominator
+	}
+	if p.WindowTargetBlocks != nil {
+		r.WindowTargetBlocks = *p.WindowTargetBlocks
+	}
+	if p.MaxBlockTxs != nil {
+		r.MaxBlockTxs = *p.MaxBlockTxs
+	}
+	if p.MaxBlockUnits != nil {
+		r.MaxBlockUnits = *p.MaxBlockUnits
+	}
+}
+
+// Rules returns the Rules in effect at the given height, computed by
+// folding every Upgrade activated at or before height onto the base
+// Genesis, in activation order. An upgrade only takes effect once both its
+// ActivationHeight and ActivationTime thresholds have been reached.
+func (g *Genesis) Rules(height uint64, timestamp int64) *Rules {
+	r := g.baseRules()
+	for _, u := range g.upgrades {
+		if u.ActivationHeight > height {
+			break
+		}
+		if u.ActivationTime > timestamp {
+			continue
+		}
+		r.apply(&u.Patch)
+	}
+	return r
+}
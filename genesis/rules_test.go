@@ -0,0 +1,101 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func ptrUint64(v uint64) *uint64 { return &v }
+
+func mustMarshalUpgrades(t *testing.T, upgrades []*Upgrade) []byte {
+	t.Helper()
+	b, err := json.Marshal(upgrades)
+	if err != nil {
+		t.Fatalf("marshal upgrades: %v", err)
+	}
+	return b
+}
+
+func TestRulesActivatesAtHeight(t *testing.T) {
+	g, err := New(nil, mustMarshalUpgrades(t, []*Upgrade{
+		{Name: "bump-price", ActivationHeight: 100, Patch: GenesisPatch{MinUnitPrice: ptrUint64(5)}},
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if got := g.Rules(99, 0).MinUnitPrice; got != g.MinUnitPrice {
+		t.Fatalf("rules before activation height should equal base genesis, got %d", got)
+	}
+	if got := g.Rules(100, 0).MinUnitPrice; got != 5 {
+		t.Fatalf("rules at activation height should reflect the upgrade, got %d", got)
+	}
+	if got := g.Rules(1_000, 0).MinUnitPrice; got != 5 {
+		t.Fatalf("rules after activation height should still reflect the upgrade, got %d", got)
+	}
+}
+
+func TestRulesActivationTimeGate(t *testing.T) {
+	g, err := New(nil, mustMarshalUpgrades(t, []*Upgrade{
+		{Name: "bump-price", ActivationHeight: 10, ActivationTime: 1_000, Patch: GenesisPatch{MinUnitPrice: ptrUint64(7)}},
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if got := g.Rules(10, 999).MinUnitPrice; got != g.MinUnitPrice {
+		t.Fatalf("upgrade should not activate before ActivationTime is reached, got %d", got)
+	}
+	if got := g.Rules(10, 1_000).MinUnitPrice; got != 7 {
+		t.Fatalf("upgrade should activate once height and time thresholds are both met, got %d", got)
+	}
+}
+
+// TestChainsDivergeOnlyAfterActivation covers the scenario the fork
+// schedule exists for: two chains launched from the same base genesis but
+// different upgradeBytes must produce identical Rules before the
+// activation height and may diverge from it onward.
+func TestChainsDivergeOnlyAfterActivation(t *testing.T) {
+	base := []byte(`{"minUnitPrice":1,"windowTargetUnits":9000000,"windowTargetBlocks":20}`)
+	upgradeA := mustMarshalUpgrades(t, []*Upgrade{{Name: "bump", ActivationHeight: 50, Patch: GenesisPatch{MinUnitPrice: ptrUint64(9)}}})
+	upgradeB := mustMarshalUpgrades(t, []*Upgrade{{Name: "bump", ActivationHeight: 50, Patch: GenesisPatch{MinUnitPrice: ptrUint64(42)}}})
+
+	gA, err := New(base, upgradeA)
+	if err != nil {
+		t.Fatalf("New gA: %v", err)
+	}
+	gB, err := New(base, upgradeB)
+	if err != nil {
+		t.Fatalf("New gB: %v", err)
+	}
+
+	for h := uint64(0); h < 50; h++ {
+		if gA.Rules(h, 0).MinUnitPrice != gB.Rules(h, 0).MinUnitPrice {
+			t.Fatalf("chains diverged before activation height at h=%d", h)
+		}
+	}
+	if gA.Rules(50, 0).MinUnitPrice == gB.Rules(50, 0).MinUnitPrice {
+		t.Fatalf("expected chains to diverge once the activation height is reached")
+	}
+}
+
+func TestParseUpgradesRejectsNonMonotonicHeights(t *testing.T) {
+	b := mustMarshalUpgrades(t, []*Upgrade{
+		{Name: "a", ActivationHeight: 100},
+		{Name: "b", ActivationHeight: 100},
+	})
+	if _, err := New(nil, b); !errors.Is(err, ErrNonMonotonicUpgrade) {
+		t.Fatalf("expected ErrNonMonotonicUpgrade, got %v", err)
+	}
+}
+
+func TestParseUpgradesRejectsUnknownFields(t *testing.T) {
+	b := []byte(`[{"name":"a","activationHeight":1,"bogusField":true}]`)
+	if _, err := New(nil, b); !errors.Is(err, ErrUnknownUpgradeField) {
+		t.Fatalf("expected ErrUnknownUpgradeField, got %v", err)
+	}
+}
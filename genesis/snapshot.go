@@ -0,0 +1,19 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import "github.com/ava-labs/avalanchego/ids"
+
+// SnapshotManifest anchors a trusted state-sync snapshot in genesis, the
+// same way go-ethereum's default sync mode trusts a pivot block: a node
+// started with --state-sync fetches and verifies the chunks it describes
+// instead of replaying every CustomAllocation.
+type SnapshotManifest struct {
+	Height      uint64   `json:"height"`
+	StateRoot   ids.ID   `json:"stateRoot"`
+	ChunkHashes []ids.ID `json:"chunkHashes"`
+	ChunkURLs   []string `json:"chunkURLs"`
+	TotalBytes  uint64   `json:"totalBytes"`
+	Algorithm   string   `json:"algorithm"`
+}
@@ -0,0 +1,255 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+
+	"github.com/ava-labs/indexvm/auth"
+	"github.com/ava-labs/indexvm/params"
+)
+
+// memDB is a minimal in-memory chain.Database fake covering only the
+// methods Genesis.Load's two code paths call. Embedding the (nil)
+// interface lets it satisfy chain.Database without stubbing out methods
+// this package never touches. GetMerkleRoot hashes the full, sorted
+// key/value set rather than any real Merkle structure, which is enough to
+// prove two independently-populated instances ended up with identical
+// contents.
+type memDB struct {
+	chain.Database
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMemDB() *memDB {
+	return &memDB{data: make(map[string][]byte)}
+}
+
+func (m *memDB) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[string(key)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memDB) Insert(_ context.Context, key, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *memDB) HasValue(_ context.Context, key []byte) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[string(key)]
+	return ok, nil
+}
+
+func (m *memDB) Remove(_ context.Context, key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, string(key))
+	return nil
+}
+
+func (m *memDB) GetMerkleRoot(context.Context) (ids.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.data))
+	for k := range m.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, k := range keys {
+		buf.WriteString(k)
+		buf.Write(m.data[k])
+	}
+	return ids.ID(hashing.ComputeHash256Array(buf.Bytes())), nil
+}
+
+func (m *memDB) dump() map[string][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]byte, len(m.data))
+	for k, v := range m.data {
+		out[k] = v
+	}
+	return out
+}
+
+// testPubKeyHex builds a deterministic, distinct hex-encoded raw ed25519
+// public key for allocation i. Going through auth.ParseKey with an
+// explicit PubKey (rather than the default Address-is-the-key path) avoids
+// needing utils.ParseAddress's bech32 decoding, which isn't checked out in
+// this tree.
+func testPubKeyHex(i byte) string {
+	return hex.EncodeToString(bytes.Repeat([]byte{i}, crypto.PublicKeyLen))
+}
+
+func testGenesis() *Genesis {
+	g := Default()
+	g.CustomAllocation = []*CustomAllocation{
+		{AuthType: auth.ED25519, PubKey: testPubKeyHex(1), Balance: 100},
+		{AuthType: auth.ED25519, PubKey: testPubKeyHex(2), Balance: 200},
+	}
+	return g
+}
+
+// TestStateSyncProducesIdenticalStateToReplay proves a node bootstrapped
+// from a SnapshotManifest ends up with the same state as one that
+// replayed CustomAllocation, which is what Load's state-sync branch (and
+// its fallback to replay on failure) both promise.
+func TestStateSyncProducesIdenticalStateToReplay(t *testing.T) {
+	ctx := context.Background()
+	tracer := trace.Noop()
+
+	replayed := newMemDB()
+	g1 := testGenesis()
+	if err := g1.Load(ctx, tracer, replayed); err != nil {
+		t.Fatalf("replay Load: %v", err)
+	}
+
+	// Build a single chunk out of everything the replay path wrote, so the
+	// snapshot path's result should be byte-for-byte the same.
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for k, v := range replayed.dump() {
+		_ = enc.Encode(struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}{hex.EncodeToString([]byte(k)), hex.EncodeToString(v)})
+	}
+	chunkBytes := body.Bytes()
+	chunkHash := ids.ID(hashing.ComputeHash256Array(chunkBytes))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(chunkBytes)
+	}))
+	defer srv.Close()
+
+	root, err := replayed.GetMerkleRoot(ctx)
+	if err != nil {
+		t.Fatalf("GetMerkleRoot: %v", err)
+	}
+
+	synced := newMemDB()
+	g2 := testGenesis()
+	g2.EnableStateSync()
+	g2.SnapshotManifest = &SnapshotManifest{
+		StateRoot:   root,
+		ChunkHashes: []ids.ID{chunkHash},
+		ChunkURLs:   []string{srv.URL},
+		Algorithm:   "sha256",
+	}
+	if err := g2.Load(ctx, tracer, synced); err != nil {
+		t.Fatalf("state-sync Load: %v", err)
+	}
+
+	want, got := replayed.dump(), synced.dump()
+	if len(want) != len(got) {
+		t.Fatalf("state-sync produced %d keys, replay produced %d", len(got), len(want))
+	}
+	for k, v := range want {
+		gv, ok := got[k]
+		if !ok || !bytes.Equal(v, gv) {
+			t.Fatalf("key %x: replay=%x state-sync=%x (present=%v)", k, v, gv, ok)
+		}
+	}
+}
+
+// TestStateSyncSeedsGovernanceAfterIndependentSnapshot proves Load seeds
+// governance only after a snapshot's root has verified, using a manifest
+// built independently of any replay output. Unlike
+// TestStateSyncProducesIdenticalStateToReplay's chunk -- which is built
+// from replayed.dump() and so already contains the replay's own
+// SetInitial keys -- this chunk contains only allocation-derived keys, so
+// a Load that seeded governance before attempting the snapshot (writing
+// extra keys db.GetMerkleRoot would then hash) would fail this snapshot's
+// root check, where that older, self-fulfilling test couldn't have
+// caught it.
+func TestStateSyncSeedsGovernanceAfterIndependentSnapshot(t *testing.T) {
+	ctx := context.Background()
+	tracer := trace.Noop()
+
+	g := testGenesis()
+	rules := g.Rules(0, 0)
+
+	source := newMemDB()
+	for _, alloc := range g.CustomAllocation {
+		if err := g.applyAllocation(ctx, source, rules, alloc); err != nil {
+			t.Fatalf("applyAllocation: %v", err)
+		}
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for k, v := range source.dump() {
+		_ = enc.Encode(struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}{hex.EncodeToString([]byte(k)), hex.EncodeToString(v)})
+	}
+	chunkBytes := body.Bytes()
+	chunkHash := ids.ID(hashing.ComputeHash256Array(chunkBytes))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(chunkBytes)
+	}))
+	defer srv.Close()
+
+	root, err := source.GetMerkleRoot(ctx)
+	if err != nil {
+		t.Fatalf("GetMerkleRoot: %v", err)
+	}
+
+	synced := newMemDB()
+	g2 := testGenesis()
+	// With no inline allocations, a fallback to replay after a failed root
+	// check would leave the allocation keys missing, so the comparison
+	// below only holds if the snapshot itself was accepted.
+	g2.CustomAllocation = nil
+	g2.EnableStateSync()
+	g2.SnapshotManifest = &SnapshotManifest{
+		StateRoot:   root,
+		ChunkHashes: []ids.ID{chunkHash},
+		ChunkURLs:   []string{srv.URL},
+		Algorithm:   "sha256",
+	}
+	if err := g2.Load(ctx, tracer, synced); err != nil {
+		t.Fatalf("state-sync Load: %v", err)
+	}
+
+	if _, err := synced.GetValue(ctx, params.ConfigKey()); err != nil {
+		t.Fatalf("expected governance to be seeded after a successful snapshot load: %v", err)
+	}
+
+	want, got := source.dump(), synced.dump()
+	for k, v := range want {
+		gv, ok := got[k]
+		if !ok || !bytes.Equal(v, gv) {
+			t.Fatalf("key %x: source=%x synced=%x (present=%v)", k, v, gv, ok)
+		}
+	}
+}
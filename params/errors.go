@@ -0,0 +1,13 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import "errors"
+
+var (
+	ErrNotGovernor           = errors.New("address is not a governor")
+	ErrAlreadyVoted          = errors.New("governor already voted on this proposal")
+	ErrVotingWindowClosed    = errors.New("proposal voting window has closed")
+	ErrProposalAlreadyPassed = errors.New("proposal already passed")
+)
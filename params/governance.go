@@ -0,0 +1,156 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// Patch is the set of Params fields a Proposal may override. A nil field
+// leaves the current value unchanged.
+type Patch struct {
+	MinUnitPrice               *uint64 `json:"minUnitPrice,omitempty"`
+	UnitPriceChangeDenominator *uint64 `json:"unitPriceChangeDenominator,omitempty"`
+	WindowTargetUnits          *uint64 `json:"windowTargetUnits,omitempty"`
+
+	MinBlockCost               *uint64 `json:"minBlockCost,omitempty"`
+	BlockCostChangeDenominator *uint64 `json:"blockCostChangeDenominator,omitempty"`
+	WindowTargetBlocks         *uint64 `json:"windowTargetBlocks,omitempty"`
+
+	StateLockup   *uint64 `json:"stateLockup,omitempty"`
+	MaxBlockUnits *uint64 `json:"maxBlockUnits,omitempty"`
+	MaxBlockTxs   *int    `json:"maxBlockTxs,omitempty"`
+}
+
+func (p *Patch) apply(params *Params) {
+	if p.MinUnitPrice != nil {
+		params.MinUnitPrice = *p.MinUnitPrice
+	}
+	if p.UnitPriceChangeDenominator != nil {
+		params.UnitPriceChangeDenominator = *p.UnitPriceChangeDenominator
+	}
+	if p.WindowTargetUnits != nil {
+		params.WindowTargetUnits = *p.WindowTargetUnits
+	}
+	if p.MinBlockCost != nil {
+		params.MinBlockCost = *p.MinBlockCost
+	}
+	if p.BlockCostChangeDenominator != nil {
+		params.BlockCostChangeDenominator = *p.BlockCostChangeDenominator
+	}
+	if p.WindowTargetBlocks != nil {
+		params.WindowTargetBlocks = *p.WindowTargetBlocks
+	}
+	if p.StateLockup != nil {
+		params.StateLockup = *p.StateLockup
+	}
+	if p.MaxBlockUnits != nil {
+		params.MaxBlockUnits = *p.MaxBlockUnits
+	}
+	if p.MaxBlockTxs != nil {
+		params.MaxBlockTxs = *p.MaxBlockTxs
+	}
+}
+
+// Proposal tracks a single in-flight or resolved param change.
+type Proposal struct {
+	ID     ids.ID `json:"id"`
+	Patch  Patch  `json:"patch"`
+	Weight uint64 `json:"weight"` // sum of Governor weight that voted yes
+
+	ProposedHeight   uint64 `json:"proposedHeight"`
+	ActivationHeight uint64 `json:"activationHeight"` // set once the proposal passes
+
+	Passed    bool `json:"passed"`
+	Activated bool `json:"activated"`
+}
+
+// Propose records a new param change proposal from a Governor. txID is used
+// as the proposal's ID so it can be looked up by the tx that created it.
+func Propose(ctx context.Context, db chain.Database, proposer crypto.PublicKey, txID ids.ID, patch Patch, height uint64) error {
+	var cfg config
+	if err := getValue(ctx, db, configKey(), &cfg); err != nil {
+		return err
+	}
+	if _, err := getGovernor(&cfg, proposer); err != nil {
+		return err
+	}
+	return putValue(ctx, db, proposalKey(txID), &Proposal{
+		ID:             txID,
+		Patch:          patch,
+		ProposedHeight: height,
+	})
+}
+
+// Vote casts voter's full weight in favor of the proposal identified by
+// proposalID. A proposal can only be voted on within ParamVotingWindow
+// blocks of being proposed. Once accumulated yes-weight crosses
+// ApprovalBps of total Governor weight, the proposal passes and is
+// scheduled to activate at height + ActivationDelay.
+func Vote(ctx context.Context, db chain.Database, voter crypto.PublicKey, proposalID ids.ID, height uint64) error {
+	var cfg config
+	if err := getValue(ctx, db, configKey(), &cfg); err != nil {
+		return err
+	}
+	gov, err := getGovernor(&cfg, voter)
+	if err != nil {
+		return err
+	}
+	var pr Proposal
+	if err := getValue(ctx, db, proposalKey(proposalID), &pr); err != nil {
+		return err
+	}
+	if pr.Passed {
+		return fmt.Errorf("%w: %s", ErrProposalAlreadyPassed, proposalID)
+	}
+	if height > pr.ProposedHeight+cfg.VotingWindow {
+		return fmt.Errorf("%w: %s", ErrVotingWindowClosed, proposalID)
+	}
+	if err := markVoted(ctx, db, proposalID, voter); err != nil {
+		return err
+	}
+	pr.Weight += gov.Weight
+	if cfg.TotalWeight > 0 && pr.Weight*10_000 >= cfg.TotalWeight*cfg.ApprovalBps {
+		pr.Passed = true
+		pr.ActivationHeight = height + cfg.ActivationDelay
+		if err := enqueuePending(ctx, db, proposalID); err != nil {
+			return err
+		}
+	}
+	return putValue(ctx, db, proposalKey(proposalID), &pr)
+}
+
+// enqueuePending appends proposalID to the queue of passed-but-not-yet-
+// activated proposals. Proposals are kept in their own queue entries
+// (rather than a single "latest passed" slot) so a second proposal
+// passing before the first one's ActivationHeight doesn't cause the first
+// to be overwritten and never activate.
+func enqueuePending(ctx context.Context, db chain.Database, proposalID ids.ID) error {
+	var pending []ids.ID
+	if err := getValue(ctx, db, pendingQueueKey(), &pending); err != nil && err != database.ErrNotFound {
+		return err
+	}
+	pending = append(pending, proposalID)
+	return putValue(ctx, db, pendingQueueKey(), pending)
+}
+
+// markVoted records that voter has already cast a ballot on proposalID,
+// rejecting a second vote from the same Governor.
+func markVoted(ctx context.Context, db chain.Database, proposalID ids.ID, voter crypto.PublicKey) error {
+	k := voteKey(proposalID, voter)
+	has, err := db.HasValue(ctx, k)
+	if err != nil {
+		return err
+	}
+	if has {
+		return fmt.Errorf("%w: %s already voted on %s", ErrAlreadyVoted, voter, proposalID)
+	}
+	return db.Insert(ctx, k, []byte{1})
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package params stores the subset of Genesis economic parameters that
+// Governors can change at runtime via a passing ProposeParamChange /
+// VoteParamChange proposal, instead of having them frozen for the lifetime
+// of the chain. Fee and limit lookups in the chain package should consult
+// this store rather than the immutable genesis.Genesis struct.
+package params
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// Params is the mutable subset of genesis.Genesis. It holds every
+// genesis.Rules field except BaseUnits, which is not governed here.
+type Params struct {
+	MinUnitPrice               uint64 `json:"minUnitPrice"`
+	UnitPriceChangeDenominator uint64 `json:"unitPriceChangeDenominator"`
+	WindowTargetUnits          uint64 `json:"windowTargetUnits"`
+
+	MinBlockCost               uint64 `json:"minBlockCost"`
+	BlockCostChangeDenominator uint64 `json:"blockCostChangeDenominator"`
+	WindowTargetBlocks         uint64 `json:"windowTargetBlocks"`
+
+	StateLockup   uint64 `json:"stateLockup"`
+	MaxBlockUnits uint64 `json:"maxBlockUnits"`
+	MaxBlockTxs   int    `json:"maxBlockTxs"`
+}
+
+// Governor is an address entitled to vote on proposals, weighted relative
+// to the other configured Governors.
+type Governor struct {
+	PublicKey crypto.PublicKey `json:"publicKey"`
+	Weight    uint64           `json:"weight"`
+}
+
+// config is the governance-process configuration fixed at genesis:
+// who may vote, what fraction of total weight is required to pass a
+// proposal, how long voting stays open, and the delay between a proposal
+// passing and its Params taking effect.
+type config struct {
+	Governors       []*Governor `json:"governors"`
+	ApprovalBps     uint64      `json:"approvalBps"`
+	VotingWindow    uint64      `json:"votingWindow"`
+	ActivationDelay uint64      `json:"activationDelay"`
+	TotalWeight     uint64      `json:"totalWeight"`
+}
+
+// SetInitial seeds the params store at genesis. It must be called exactly
+// once, from Genesis.Load, before any proposal can be made.
+func SetInitial(ctx context.Context, db chain.Database, p *Params, governors []*Governor, approvalBps, votingWindow, activationDelay uint64) error {
+	var total uint64
+	for _, gov := range governors {
+		total += gov.Weight
+	}
+	cfg := &config{
+		Governors:       governors,
+		ApprovalBps:     approvalBps,
+		VotingWindow:    votingWindow,
+		ActivationDelay: activationDelay,
+		TotalWeight:     total,
+	}
+	if err := putValue(ctx, db, configKey(), cfg); err != nil {
+		return err
+	}
+	return putValue(ctx, db, currentParamsKey(), p)
+}
+
+// Get returns the Params in effect at the given height: the current
+// persisted Params with the Patch of every passed proposal whose
+// ActivationHeight has been reached folded in. Get is a pure read -- it
+// never persists the fold, so it's safe to call from a read-only path
+// (fee simulation, an API handler) without that path having to declare
+// any StateKeys beyond the ones it reads. It is the chain package's job to
+// call Activate once per block, deterministically, so activated proposals
+// are actually dropped from the pending queue; Get folding them in on
+// every call is what keeps correct values visible even at heights where
+// Activate hasn't run yet (e.g. a query against a not-yet-committed
+// height).
+func Get(ctx context.Context, db chain.Database, height uint64) (*Params, error) {
+	var p Params
+	if err := getValue(ctx, db, currentParamsKey(), &p); err != nil {
+		return nil, err
+	}
+	due, _, err := duePending(ctx, db, height)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range due {
+		pr.Patch.apply(&p)
+	}
+	return &p, nil
+}
+
+// Activate folds every passed proposal whose ActivationHeight has been
+// reached as of height into the current Params and removes it from the
+// pending queue, so future Get/Activate calls don't re-walk history.
+// Unlike Get, Activate mutates currentParamsKey, pendingQueueKey, and each
+// activated proposal's ProposalKey -- it must be called exactly once per
+// block height, from a deterministic per-block step in the chain package,
+// never from a read-only path.
+func Activate(ctx context.Context, db chain.Database, height uint64) error {
+	due, remaining, err := duePending(ctx, db, height)
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	var p Params
+	if err := getValue(ctx, db, currentParamsKey(), &p); err != nil {
+		return err
+	}
+	for _, pr := range due {
+		pr.Patch.apply(&p)
+		pr.Activated = true
+		if err := putValue(ctx, db, proposalKey(pr.ID), pr); err != nil {
+			return err
+		}
+	}
+	if err := putValue(ctx, db, currentParamsKey(), &p); err != nil {
+		return err
+	}
+	return putValue(ctx, db, pendingQueueKey(), remaining)
+}
+
+// duePending reads (without writing) the pending queue and splits it into
+// proposals whose ActivationHeight has been reached as of height (due, in
+// the order they were enqueued) and those that haven't (remaining, kept
+// queued in the same order). Proposals not yet due stay queued so a later
+// proposal passing first doesn't cause an earlier one to be lost, unlike
+// overwriting a single "latest passed" slot would.
+func duePending(ctx context.Context, db chain.Database, height uint64) (due []*Proposal, remaining []ids.ID, err error) {
+	var pending []ids.ID
+	if err := getValue(ctx, db, pendingQueueKey(), &pending); err != nil {
+		if err == database.ErrNotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	remaining = make([]ids.ID, 0, len(pending))
+	for _, id := range pending {
+		var pr Proposal
+		if err := getValue(ctx, db, proposalKey(id), &pr); err != nil {
+			return nil, nil, err
+		}
+		if height < pr.ActivationHeight {
+			remaining = append(remaining, id)
+			continue
+		}
+		prCopy := pr
+		due = append(due, &prCopy)
+	}
+	return due, remaining, nil
+}
+
+func getGovernor(cfg *config, pk crypto.PublicKey) (*Governor, error) {
+	for _, gov := range cfg.Governors {
+		if gov.PublicKey == pk {
+			return gov, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is not a governor", ErrNotGovernor, pk)
+}
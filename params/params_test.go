@@ -0,0 +1,262 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// memDB is a minimal in-memory chain.Database fake covering only the
+// methods this package calls (GetValue/Insert/HasValue). Embedding the
+// (nil) interface lets it satisfy chain.Database without stubbing out
+// methods this package never touches.
+type memDB struct {
+	chain.Database
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMemDB() *memDB {
+	return &memDB{data: make(map[string][]byte)}
+}
+
+func (m *memDB) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[string(key)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memDB) Insert(_ context.Context, key, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *memDB) HasValue(_ context.Context, key []byte) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[string(key)]
+	return ok, nil
+}
+
+func (m *memDB) dump() map[string][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]byte, len(m.data))
+	for k, v := range m.data {
+		out[k] = v
+	}
+	return out
+}
+
+func testGovernor(discriminator byte, weight uint64) (*Governor, crypto.PublicKey) {
+	var pk crypto.PublicKey
+	pk[0] = discriminator
+	return &Governor{PublicKey: pk, Weight: weight}, pk
+}
+
+// TestVoteActivatesProposalAfterDelay proves a passed proposal actually
+// changes pricing, and only once currentHeight+ParamActivationDelay is
+// reached, not before.
+func TestVoteActivatesProposalAfterDelay(t *testing.T) {
+	ctx := context.Background()
+	db := newMemDB()
+
+	govA, pkA := testGovernor(1, 1)
+	govB, pkB := testGovernor(2, 2)
+
+	base := &Params{MinUnitPrice: 1, WindowTargetUnits: 9_000_000, WindowTargetBlocks: 20}
+	const activationDelay = 5
+	if err := SetInitial(ctx, db, base, []*Governor{govA, govB}, 5_000, 10, activationDelay); err != nil {
+		t.Fatalf("SetInitial: %v", err)
+	}
+
+	txID := ids.GenerateTestID()
+	newPrice := uint64(99)
+	if err := Propose(ctx, db, pkA, txID, Patch{MinUnitPrice: &newPrice}, 100); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+
+	// govA alone (weight 1 of 3) is short of the 50% approval threshold.
+	if err := Vote(ctx, db, pkA, txID, 101); err != nil {
+		t.Fatalf("Vote (govA): %v", err)
+	}
+	if p, err := Get(ctx, db, 101); err != nil {
+		t.Fatalf("Get: %v", err)
+	} else if p.MinUnitPrice != base.MinUnitPrice {
+		t.Fatalf("price should not change before the proposal passes, got %d", p.MinUnitPrice)
+	}
+
+	// govB joining pushes accumulated weight to 3 of 3, over threshold.
+	const voteHeight = 101
+	if err := Vote(ctx, db, pkB, txID, voteHeight); err != nil {
+		t.Fatalf("Vote (govB): %v", err)
+	}
+
+	activationHeight := uint64(voteHeight + activationDelay)
+	if p, err := Get(ctx, db, activationHeight-1); err != nil {
+		t.Fatalf("Get: %v", err)
+	} else if p.MinUnitPrice != base.MinUnitPrice {
+		t.Fatalf("price should not change before currentHeight+ParamActivationDelay, got %d", p.MinUnitPrice)
+	}
+
+	p, err := Get(ctx, db, activationHeight)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.MinUnitPrice != newPrice {
+		t.Fatalf("expected price %d at activation height, got %d", newPrice, p.MinUnitPrice)
+	}
+}
+
+// TestGetDoesNotMutateState guards against Get regressing into the bug
+// where a read-only path (fee simulation, an API handler) silently wrote
+// to the pending queue and current Params as a side effect of answering a
+// query.
+func TestGetDoesNotMutateState(t *testing.T) {
+	ctx := context.Background()
+	db := newMemDB()
+
+	gov, pk := testGovernor(1, 1)
+	if err := SetInitial(ctx, db, &Params{MinUnitPrice: 1}, []*Governor{gov}, 5_000, 10, 5); err != nil {
+		t.Fatalf("SetInitial: %v", err)
+	}
+	txID := ids.GenerateTestID()
+	newPrice := uint64(9)
+	if err := Propose(ctx, db, pk, txID, Patch{MinUnitPrice: &newPrice}, 0); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if err := Vote(ctx, db, pk, txID, 1); err != nil {
+		t.Fatalf("Vote: %v", err)
+	} // passes at height 1, activates at height 6
+
+	before := db.dump()
+	if p, err := Get(ctx, db, 100); err != nil {
+		t.Fatalf("Get: %v", err)
+	} else if p.MinUnitPrice != newPrice {
+		t.Fatalf("Get should still report the activated price at a later height, got %d", p.MinUnitPrice)
+	}
+	after := db.dump()
+
+	if len(before) != len(after) {
+		t.Fatalf("Get changed the number of stored keys: %d -> %d", len(before), len(after))
+	}
+	for k, v := range before {
+		if av, ok := after[k]; !ok || string(av) != string(v) {
+			t.Fatalf("Get mutated key %x", k)
+		}
+	}
+}
+
+// TestActivatePersistsAndDropsFromQueue proves Activate, unlike Get,
+// actually folds a due proposal into the persisted Params and removes it
+// from the pending queue.
+func TestActivatePersistsAndDropsFromQueue(t *testing.T) {
+	ctx := context.Background()
+	db := newMemDB()
+
+	gov, pk := testGovernor(1, 1)
+	if err := SetInitial(ctx, db, &Params{MinUnitPrice: 1}, []*Governor{gov}, 5_000, 10, 5); err != nil {
+		t.Fatalf("SetInitial: %v", err)
+	}
+	txID := ids.GenerateTestID()
+	newPrice := uint64(9)
+	if err := Propose(ctx, db, pk, txID, Patch{MinUnitPrice: &newPrice}, 0); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if err := Vote(ctx, db, pk, txID, 1); err != nil {
+		t.Fatalf("Vote: %v", err)
+	} // passes at height 1, activates at height 6
+
+	if err := Activate(ctx, db, 6); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+
+	var p Params
+	if err := getValue(ctx, db, currentParamsKey(), &p); err != nil {
+		t.Fatalf("getValue currentParams: %v", err)
+	}
+	if p.MinUnitPrice != newPrice {
+		t.Fatalf("Activate should have persisted the activated price, got %d", p.MinUnitPrice)
+	}
+
+	var pending []ids.ID
+	if err := getValue(ctx, db, pendingQueueKey(), &pending); err != nil {
+		t.Fatalf("getValue pendingQueue: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("Activate should have dropped the activated proposal from the queue, got %d left", len(pending))
+	}
+}
+
+func TestProposeRejectsNonGovernor(t *testing.T) {
+	ctx := context.Background()
+	db := newMemDB()
+	gov, _ := testGovernor(1, 1)
+	if err := SetInitial(ctx, db, &Params{}, []*Governor{gov}, 5_000, 10, 5); err != nil {
+		t.Fatalf("SetInitial: %v", err)
+	}
+
+	txID := ids.GenerateTestID()
+	var stranger crypto.PublicKey
+	stranger[0] = 0xFF
+	if err := Propose(ctx, db, stranger, txID, Patch{}, 0); !errors.Is(err, ErrNotGovernor) {
+		t.Fatalf("expected ErrNotGovernor, got %v", err)
+	}
+}
+
+// TestTwoPendingProposalsBothActivate guards against the bug where a
+// second proposal passing before the first's ActivationHeight overwrote a
+// single "latest passed" slot and the first proposal never activated.
+func TestTwoPendingProposalsBothActivate(t *testing.T) {
+	ctx := context.Background()
+	db := newMemDB()
+
+	gov, pk := testGovernor(1, 1)
+	if err := SetInitial(ctx, db, &Params{MinUnitPrice: 1, MaxBlockTxs: 10}, []*Governor{gov}, 5_000, 100, 10); err != nil {
+		t.Fatalf("SetInitial: %v", err)
+	}
+
+	priceTxID := ids.GenerateTestID()
+	newPrice := uint64(7)
+	if err := Propose(ctx, db, pk, priceTxID, Patch{MinUnitPrice: &newPrice}, 0); err != nil {
+		t.Fatalf("Propose price: %v", err)
+	}
+	if err := Vote(ctx, db, pk, priceTxID, 1); err != nil {
+		t.Fatalf("Vote price: %v", err)
+	} // passes at height 1, activates at height 11
+
+	txsTxID := ids.GenerateTestID()
+	newMaxTxs := 20
+	if err := Propose(ctx, db, pk, txsTxID, Patch{MaxBlockTxs: &newMaxTxs}, 1); err != nil {
+		t.Fatalf("Propose maxTxs: %v", err)
+	}
+	if err := Vote(ctx, db, pk, txsTxID, 2); err != nil {
+		t.Fatalf("Vote maxTxs: %v", err)
+	} // passes at height 2 -- before the price proposal activates at 11 -- and activates at height 12
+
+	p, err := Get(ctx, db, 12)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.MaxBlockTxs != newMaxTxs {
+		t.Fatalf("expected the second proposal to activate, got MaxBlockTxs=%d", p.MaxBlockTxs)
+	}
+	if p.MinUnitPrice != newPrice {
+		t.Fatalf("expected the first proposal to still activate once its own height was reached, got MinUnitPrice=%d", p.MinUnitPrice)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// moduleKeyPrefix namespaces every key this package writes into its own
+// byte range of the shared chain.Database keyspace, distinct from the
+// storage package's account-state keys (which start at 0x0/0x1). All
+// params keys are moduleKeyPrefix followed by one of the sub-prefixes
+// below.
+const moduleKeyPrefix byte = 0x50
+
+const (
+	configKeySuffix   byte = 0x0 // singleton: governance config
+	paramsKeySuffix   byte = 0x1 // singleton: current Params
+	proposalKeySuffix byte = 0x2 // proposalID -> Proposal
+	pendingKeySuffix  byte = 0x3 // singleton: []ids.ID of passed-but-not-yet-activated proposals
+	voteKeySuffix     byte = 0x4 // proposalID || voter -> ballot marker
+)
+
+// ConfigKey, ProposalKey, VoteKey, CurrentParamsKey, and PendingQueueKey
+// are exported so chain.Action implementations in the actions package can
+// declare exactly the keys their Execute touches via StateKeys.
+func ConfigKey() []byte                    { return configKey() }
+func CurrentParamsKey() []byte             { return currentParamsKey() }
+func ProposalKey(proposalID ids.ID) []byte { return proposalKey(proposalID) }
+func PendingQueueKey() []byte              { return pendingQueueKey() }
+
+func VoteKey(proposalID ids.ID, voter crypto.PublicKey) []byte {
+	return voteKey(proposalID, voter)
+}
+
+func configKey() []byte {
+	return []byte{moduleKeyPrefix, configKeySuffix}
+}
+
+func currentParamsKey() []byte {
+	return []byte{moduleKeyPrefix, paramsKeySuffix}
+}
+
+func proposalKey(proposalID ids.ID) []byte {
+	k := make([]byte, 0, 2+ids.IDLen)
+	k = append(k, moduleKeyPrefix, proposalKeySuffix)
+	k = append(k, proposalID[:]...)
+	return k
+}
+
+func pendingQueueKey() []byte {
+	return []byte{moduleKeyPrefix, pendingKeySuffix}
+}
+
+func voteKey(proposalID ids.ID, voter crypto.PublicKey) []byte {
+	k := make([]byte, 0, 2+ids.IDLen+len(voter))
+	k = append(k, moduleKeyPrefix, voteKeySuffix)
+	k = append(k, proposalID[:]...)
+	k = append(k, voter[:]...)
+	return k
+}
+
+// getValue reads and JSON-decodes the value stored at k into v.
+func getValue(ctx context.Context, db chain.Database, k []byte, v interface{}) error {
+	b, err := db.GetValue(ctx, k)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// putValue JSON-encodes v and stores it at k.
+func putValue(ctx context.Context, db chain.Database, k []byte, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return db.Insert(ctx, k, b)
+}
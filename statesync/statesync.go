@@ -0,0 +1,173 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package statesync bootstraps a node's chain.Database from a genesis
+// SnapshotManifest instead of replaying every genesis allocation, the way
+// go-ethereum's snap-sync fetches a trusted pivot state over the network.
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+var (
+	ErrChunkCountMismatch   = errors.New("manifest has a different number of chunk hashes than chunk URLs")
+	ErrUnsupportedAlgorithm = errors.New("unsupported state-sync algorithm")
+	ErrChunkHashMismatch    = errors.New("fetched chunk does not match its manifest hash")
+	ErrRootMismatch         = errors.New("computed state root does not match manifest")
+	ErrChunkTooLarge        = errors.New("chunk exceeds the manifest's declared size bound")
+)
+
+// maxChunkBytesFallback bounds a single chunk's body when m.TotalBytes is
+// unset, so a manifest that omits it still can't make loadChunk buffer an
+// unbounded response before the hash check ever runs.
+const maxChunkBytesFallback = 1 << 30 // 1 GiB
+
+// Manifest is the trusted anchor needed to fetch and verify a pre-built
+// state snapshot. It mirrors genesis.SnapshotManifest field-for-field; the
+// two are kept as separate types so this package doesn't import genesis.
+type Manifest struct {
+	Height      uint64
+	StateRoot   ids.ID
+	ChunkHashes []ids.ID
+	ChunkURLs   []string
+	TotalBytes  uint64
+	Algorithm   string
+}
+
+// Load fetches every chunk in m over HTTP, verifies it against its
+// declared hash, and inserts its key/value pairs into db. It asserts the
+// resulting state root matches m.StateRoot before returning. On any
+// failure -- a chunk that can't be fetched, a chunk hash mismatch, or a
+// final root mismatch -- every key Load itself inserted is removed from db
+// before the error is returned, so callers that fall back to full
+// allocation replay never do so on top of a partially-applied snapshot; a
+// bad or unreachable snapshot never leaves a node launched in a
+// half-loaded state.
+func Load(ctx context.Context, tracer trace.Tracer, db chain.Database, m *Manifest) error {
+	ctx, span := tracer.Start(ctx, "statesync.Load")
+	defer span.End()
+
+	if m.Algorithm != "sha256" {
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, m.Algorithm)
+	}
+	if len(m.ChunkHashes) != len(m.ChunkURLs) {
+		return fmt.Errorf("%w: %d hashes, %d urls", ErrChunkCountMismatch, len(m.ChunkHashes), len(m.ChunkURLs))
+	}
+
+	limit := int64(m.TotalBytes)
+	if limit <= 0 {
+		limit = maxChunkBytesFallback
+	}
+
+	var inserted [][]byte
+	for i, url := range m.ChunkURLs {
+		keys, err := loadChunk(ctx, tracer, db, url, m.ChunkHashes[i], i, limit)
+		inserted = append(inserted, keys...)
+		if err != nil {
+			return rollback(ctx, db, inserted, err)
+		}
+	}
+
+	root, err := db.GetMerkleRoot(ctx)
+	if err != nil {
+		return rollback(ctx, db, inserted, err)
+	}
+	if root != m.StateRoot {
+		return rollback(ctx, db, inserted, fmt.Errorf("%w: got %s, want %s", ErrRootMismatch, root, m.StateRoot))
+	}
+	return nil
+}
+
+// rollback removes every key in keys, most recently inserted first, and
+// returns origErr -- joined with any error encountered while rolling back,
+// so a failed removal is never silently swallowed.
+func rollback(ctx context.Context, db chain.Database, keys [][]byte, origErr error) error {
+	var rollbackErr error
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := db.Remove(ctx, keys[i]); err != nil {
+			rollbackErr = errors.Join(rollbackErr, err)
+		}
+	}
+	if rollbackErr != nil {
+		return errors.Join(origErr, fmt.Errorf("rolling back partial state sync: %w", rollbackErr))
+	}
+	return origErr
+}
+
+// loadChunk fetches url and applies its contents, refusing to buffer more
+// than limit bytes of response body -- a mis-specified or malicious
+// chunkURL otherwise has no reason not to serve an unbounded stream and
+// OOM the bootstrapping node before the hash check below ever runs.
+func loadChunk(ctx context.Context, tracer trace.Tracer, db chain.Database, url string, want ids.ID, idx int, limit int64) ([][]byte, error) {
+	ctx, span := tracer.Start(ctx, "statesync.loadChunk")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching chunk %d: %w", idx, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching chunk %d: unexpected status %s", idx, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading chunk %d: %w", idx, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: chunk %d (limit %d bytes)", ErrChunkTooLarge, idx, limit)
+	}
+	if got := ids.ID(hashing.ComputeHash256Array(body)); got != want {
+		return nil, fmt.Errorf("%w: chunk %d (got %s, want %s)", ErrChunkHashMismatch, idx, got, want)
+	}
+	return applyChunk(ctx, db, body)
+}
+
+// applyChunk decodes body as a stream of hex-encoded {key, value} JSON
+// objects and inserts each into db, returning every key inserted so far
+// even if it stops on a decode or insert error partway through, so the
+// caller can still roll those back.
+func applyChunk(ctx context.Context, db chain.Database, body []byte) ([][]byte, error) {
+	var inserted [][]byte
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for dec.More() {
+		var kv struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+		if err := dec.Decode(&kv); err != nil {
+			return inserted, err
+		}
+		k, err := hex.DecodeString(kv.Key)
+		if err != nil {
+			return inserted, err
+		}
+		v, err := hex.DecodeString(kv.Value)
+		if err != nil {
+			return inserted, err
+		}
+		if err := db.Insert(ctx, k, v); err != nil {
+			return inserted, err
+		}
+		inserted = append(inserted, k)
+	}
+	return inserted, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+
+	"github.com/ava-labs/indexvm/auth"
+)
+
+// authTypeKeyPrefix identifies the per-address entry recording which
+// auth.All scheme a tx from that address must be signed and verified
+// under. It follows this package's existing per-address key layout
+// (prefix byte || address), using the next unused prefix.
+const authTypeKeyPrefix byte = 0x3
+
+func authTypeKey(pk crypto.PublicKey) []byte {
+	k := make([]byte, 0, 1+len(pk))
+	k = append(k, authTypeKeyPrefix)
+	k = append(k, pk[:]...)
+	return k
+}
+
+// SetAuthType records the auth scheme a genesis allocation's address was
+// created under. This package only stores that record; nothing in this
+// tree's chain.Database-level code enforces it against an incoming tx --
+// that enforcement (Auth.Verify consulting GetAuthType before accepting a
+// tx) belongs to the chain package's auth dispatch, which isn't checked
+// out here. Until that wiring exists, a BLS/secp256r1 allocation recorded
+// here is not actually protected from being spent under a different
+// scheme.
+func SetAuthType(ctx context.Context, db chain.Database, pk crypto.PublicKey, authType string) error {
+	return db.Insert(ctx, authTypeKey(pk), []byte(authType))
+}
+
+// GetAuthType returns the auth scheme previously recorded for pk by
+// SetAuthType, or auth.ED25519 if none was ever set (the default for any
+// address that predates this package knowing about other schemes).
+func GetAuthType(ctx context.Context, db chain.Database, pk crypto.PublicKey) (string, error) {
+	b, err := db.GetValue(ctx, authTypeKey(pk))
+	if err == database.ErrNotFound {
+		return auth.ED25519, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}